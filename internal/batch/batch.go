@@ -0,0 +1,104 @@
+// Package batch는 여러 타겟을 제한된 동시성(worker pool)으로 한 번에 스캔하고,
+// 입력 순서를 보존한 결과 목록을 만듭니다. 루트의 Netlify HTTP 서버와
+// netlify/functions/check-ssl의 Lambda 핸들러가 이 패키지를 공유합니다.
+package batch
+
+import (
+	"context"
+	"net/url"
+	"sync"
+
+	"github.com/zasfe/ssl-checker-go/internal/certcheck"
+	"github.com/zasfe/ssl-checker-go/internal/starttls"
+	"github.com/zasfe/ssl-checker-go/internal/trust"
+)
+
+// DefaultConcurrency는 concurrency가 지정되지 않았을 때 사용하는 동시 처리 수입니다.
+const DefaultConcurrency = 16
+
+// Target은 배치로 스캔할 단일 대상입니다.
+type Target struct {
+	URL      string `json:"url"`
+	IP       string `json:"ip,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Trust    string `json:"trust,omitempty"` // system(기본)/mozilla/custom
+	CA       string `json:"ca,omitempty"`    // trust=custom일 때 사용할 PEM 번들
+}
+
+// Result는 하나의 Target에 대한 스캔 결과입니다. Response와 Error는 서로 배타적입니다.
+type Result struct {
+	Target   Target              `json:"target"`
+	Response *certcheck.Response `json:"response,omitempty"`
+	Error    string              `json:"error,omitempty"`
+}
+
+// Run은 targets를 concurrency만큼의 워커로 동시에 스캔합니다. concurrency가 0 이하이면
+// DefaultConcurrency를 사용합니다. 각 타겟은 독립적으로 실패할 수 있으며, 실패해도 나머지
+// 타겟의 처리를 막지 않습니다. 반환되는 슬라이스는 targets와 같은 순서를 유지합니다.
+func Run(ctx context.Context, targets []Target, concurrency int) []Result {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([]Result, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, target Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = scanOne(ctx, target)
+		}(i, target)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func scanOne(ctx context.Context, target Target) Result {
+	result := Result{Target: target}
+
+	protocol := starttls.Protocol(target.Protocol)
+	if protocol == "" {
+		protocol = starttls.ProtocolHTTPS
+	}
+
+	parsedURL, err := url.Parse(target.URL)
+	if err != nil || parsedURL.Host == "" {
+		parsedURL, err = url.Parse("//" + target.URL)
+	}
+	if err != nil || parsedURL.Host == "" {
+		result.Error = "invalid 'url' field"
+		return result
+	}
+	serverName := parsedURL.Hostname()
+
+	port := parsedURL.Port()
+	if port == "" {
+		var ok bool
+		port, ok = starttls.DefaultPort(protocol)
+		if !ok {
+			port = "443"
+		}
+	}
+
+	// ip가 지정되면 해당 IP로 연결하되, SNI/검증에는 여전히 호스트명을 사용합니다
+	// (기존 Lambda 핸들러와 동일한 동작입니다).
+	connectHost := serverName
+	if target.IP != "" {
+		connectHost = target.IP
+	}
+	address := connectHost + ":" + port
+
+	resp, err := certcheck.Check(ctx, address, serverName, protocol, false, trust.Store(target.Trust), []byte(target.CA))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Response = resp
+	return result
+}
@@ -0,0 +1,45 @@
+package batch
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRunPreservesOrderAndReportsPerTargetErrors(t *testing.T) {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+
+	targets := []Target{
+		{URL: serverURL.Host, Protocol: "https"},
+		{URL: "", Protocol: "https"}, // invalid: empty URL
+		{URL: serverURL.Host, Protocol: "https"},
+	}
+
+	results := Run(context.Background(), targets, 2)
+
+	if len(results) != len(targets) {
+		t.Fatalf("expected %d results, got %d", len(targets), len(results))
+	}
+	if results[0].Error != "" || results[0].Response == nil {
+		t.Fatalf("expected target 0 to succeed, got error %q", results[0].Error)
+	}
+	if results[1].Error == "" {
+		t.Fatalf("expected target 1 to fail with an error")
+	}
+	if results[2].Error != "" || results[2].Response == nil {
+		t.Fatalf("expected target 2 to succeed, got error %q", results[2].Error)
+	}
+}
+
+func TestRunDefaultConcurrency(t *testing.T) {
+	if DefaultConcurrency <= 0 {
+		t.Fatalf("expected positive DefaultConcurrency, got %d", DefaultConcurrency)
+	}
+}
@@ -0,0 +1,190 @@
+// Package certcheck는 TLS 연결을 맺고 인증서 체인을 조회/검증하는 공통 로직을 담고 있습니다.
+// 루트의 Netlify HTTP 핸들러와 netlify/functions/check-ssl의 Lambda 핸들러가 이 패키지를
+// 공유합니다.
+package certcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/zasfe/ssl-checker-go/internal/revoke"
+	"github.com/zasfe/ssl-checker-go/internal/starttls"
+	"github.com/zasfe/ssl-checker-go/internal/trust"
+)
+
+// CertInfo는 개별 인증서의 상세 정보를 담는 구조체입니다.
+type CertInfo struct {
+	Subject       string         `json:"subject"`
+	Issuer        string         `json:"issuer"`
+	NotBefore     time.Time      `json:"not_before"`
+	NotAfter      time.Time      `json:"not_after"`
+	DNSNames      []string       `json:"dns_names,omitempty"` // 사이트 인증서에만 포함
+	IsCA          bool           `json:"is_ca"`
+	SignatureAlgo string         `json:"signature_algorithm"`
+	Revocation    *revoke.Result `json:"revocation,omitempty"`
+
+	SerialNumberHex       string   `json:"serial_number_hex"`
+	SHA1Fingerprint       string   `json:"sha1_fingerprint"`
+	SHA256Fingerprint     string   `json:"sha256_fingerprint"`
+	PublicKeyAlgorithm    string   `json:"public_key_algorithm"`
+	PublicKeySize         string   `json:"public_key_size"`
+	IssuingCertificateURL []string `json:"issuing_certificate_url,omitempty"`
+	OCSPServer            []string `json:"ocsp_server,omitempty"`
+	CRLDistributionPoints []string `json:"crl_distribution_points,omitempty"`
+	ExtKeyUsages          []string `json:"extended_key_usages,omitempty"`
+	SCTs                  []SCTInfo `json:"scts,omitempty"`
+	PEM                   string   `json:"pem"`
+}
+
+// Response는 API 응답의 전체 구조입니다.
+type Response struct {
+	TargetURL       string     `json:"target_url"`
+	Protocol        string     `json:"protocol"`
+	TLSVersion      string     `json:"tls_version"`
+	CipherSuite     string     `json:"cipher_suite"`
+	TrustStore      string     `json:"trust_store"`
+	Certificates    []CertInfo `json:"certificates"`
+	ChainValidation string     `json:"chain_validation_message"`
+	VerifiedChain   []string   `json:"verified_chain,omitempty"` // leaf -> root Subject 목록
+}
+
+// DialTimeout은 TLS 핸드셰이크(및 STARTTLS 프리앰블)에 사용할 기본 타임아웃입니다.
+const DialTimeout = 5 * time.Second
+
+// Check는 address(호스트:포트)로 연결해 필요하면 protocol에 맞는 STARTTLS 프리앰블을
+// 수행한 뒤, serverName을 SNI/검증에 사용해 인증서 체인을 조회합니다. strict가 true이면
+// 폐기 여부를 unknown으로도 판단할 수 없을 때 에러를 반환합니다. trustStore는 체인
+// 검증에 사용할 루트 집합을 고릅니다(system/mozilla/custom); custom이면 customCAPEM이
+// 그 루트 번들입니다.
+func Check(ctx context.Context, address, serverName string, protocol starttls.Protocol, strict bool, trustStore trust.Store, customCAPEM []byte) (*Response, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, DialTimeout)
+	defer cancel()
+
+	conn, err := starttls.Dial(dialCtx, address, protocol, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect via TLS: %w", err)
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("server did not provide any certificates")
+	}
+
+	certInfos := make([]CertInfo, 0, len(certs))
+	for i, cert := range certs {
+		info := CertInfo{
+			Subject:       cert.Subject.String(),
+			Issuer:        cert.Issuer.String(),
+			NotBefore:     cert.NotBefore.UTC(),
+			NotAfter:      cert.NotAfter.UTC(),
+			IsCA:          cert.IsCA,
+			SignatureAlgo: cert.SignatureAlgorithm.String(),
+		}
+		if i == 0 {
+			info.DNSNames = cert.DNSNames
+		}
+		populateDetails(&info, cert)
+
+		// 루트 인증서는 자기 자신이 발급자이므로 폐기 조회 대상에서 제외합니다. 서버가
+		// 체인 맨 끝에 루트를 보내지 않는 것이 일반적인 관행이므로, 체인에서의 위치가
+		// 아니라 자체 서명 여부로 루트를 판별합니다.
+		if !isSelfSigned(cert) {
+			issuer := issuerFor(certs, i)
+			if issuer != nil {
+				result := revoke.Check(ctx, cert, issuer)
+				info.Revocation = &result
+				if strict && result.Status == revoke.StatusUnknown {
+					return nil, fmt.Errorf("revocation status unknown for %s (strict mode)", cert.Subject.String())
+				}
+			}
+		}
+
+		certInfos = append(certInfos, info)
+	}
+
+	intermediates := x509.NewCertPool()
+	for i, cert := range certs {
+		if i > 0 {
+			intermediates.AddCert(cert)
+		}
+	}
+
+	roots, trustStoreName, err := trust.Pool(trustStore, customCAPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trust store: %w", err)
+	}
+	if trustStore == trust.StoreSystem || trustStore == "" {
+		roots = nil // nil Roots는 x509가 OS 기본 풀을 사용하도록 합니다.
+	}
+
+	validationMessage := "Certificate chain is valid."
+	var verifiedChain []string
+	validationOpts := x509.VerifyOptions{
+		DNSName:       serverName,
+		Intermediates: intermediates,
+		Roots:         roots,
+	}
+	if chains, err := certs[0].Verify(validationOpts); err != nil {
+		validationMessage = fmt.Sprintf("Certificate chain verification failed: %s", err.Error())
+	} else if len(chains) > 0 {
+		for _, cert := range chains[0] {
+			verifiedChain = append(verifiedChain, cert.Subject.String())
+		}
+	}
+
+	connState := conn.ConnectionState()
+
+	return &Response{
+		TargetURL:       fmt.Sprintf("https://%s", serverName),
+		Protocol:        string(protocol),
+		TLSVersion:      tlsVersionName(connState.Version),
+		CipherSuite:     tls.CipherSuiteName(connState.CipherSuite),
+		TrustStore:      trustStoreName,
+		Certificates:    certInfos,
+		ChainValidation: validationMessage,
+		VerifiedChain:   verifiedChain,
+	}, nil
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("unknown (0x%04x)", version)
+	}
+}
+
+// issuerFor는 certs[i]의 발급자 인증서를 체인에서 찾습니다. 찾지 못하면 nil을 반환합니다
+// (예: 루트가 체인에 포함되지 않은 경우).
+func issuerFor(certs []*x509.Certificate, i int) *x509.Certificate {
+	if i+1 < len(certs) {
+		return certs[i+1]
+	}
+	// 자체 서명 루트는 호출자가 이미 제외하므로, 마지막 인증서의 발급자는 체인에 없습니다.
+	return nil
+}
+
+// isSelfSigned는 cert가 자기 자신을 발급자로 하는(즉, 체인의 진짜 루트인) 인증서인지
+// 판별합니다. 서버가 체인 맨 끝에 루트를 포함하지 않는 경우가 흔하므로, PeerCertificates
+// 에서의 위치가 아니라 서명 검증으로 판별해야 합니다.
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+		return false
+	}
+	return cert.CheckSignatureFrom(cert) == nil
+}
@@ -0,0 +1,148 @@
+package certcheck
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"time"
+)
+
+// sctListOID는 RFC 6962의 embedded SCT list X.509v3 extension OID입니다.
+var sctListOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+
+// SCTInfo는 인증서에 내장된 하나의 Signed Certificate Timestamp입니다.
+type SCTInfo struct {
+	Version   int       `json:"version"`
+	LogID     string    `json:"log_id"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// populateDetails는 핑거프린트, 공개키 정보, AIA/OCSP/CRL 확장, EKU, SCT, PEM 인코딩 등
+// openssl s_client -showcerts 수준의 세부 정보로 info를 채웁니다.
+func populateDetails(info *CertInfo, cert *x509.Certificate) {
+	sum1 := sha1.Sum(cert.Raw)
+	sum256 := sha256.Sum256(cert.Raw)
+	info.SHA1Fingerprint = hex.EncodeToString(sum1[:])
+	info.SHA256Fingerprint = hex.EncodeToString(sum256[:])
+
+	info.SerialNumberHex = cert.SerialNumber.Text(16)
+
+	algo, size := publicKeyDetails(cert.PublicKey)
+	info.PublicKeyAlgorithm = algo
+	info.PublicKeySize = size
+
+	info.IssuingCertificateURL = cert.IssuingCertificateURL
+	info.OCSPServer = cert.OCSPServer
+	info.CRLDistributionPoints = cert.CRLDistributionPoints
+
+	for _, eku := range cert.ExtKeyUsage {
+		info.ExtKeyUsages = append(info.ExtKeyUsages, extKeyUsageName(eku))
+	}
+
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(sctListOID) {
+			if scts, err := parseSCTList(ext.Value); err == nil {
+				info.SCTs = scts
+			}
+			break
+		}
+	}
+
+	info.PEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+}
+
+func publicKeyDetails(pub interface{}) (algorithm, size string) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return "RSA", fmt.Sprintf("%d bits", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA", fmt.Sprintf("%s (%d bits)", key.Curve.Params().Name, key.Curve.Params().BitSize)
+	case ed25519.PublicKey:
+		return "Ed25519", fmt.Sprintf("%d bits", len(key)*8)
+	default:
+		return "unknown", "unknown"
+	}
+}
+
+func extKeyUsageName(eku x509.ExtKeyUsage) string {
+	switch eku {
+	case x509.ExtKeyUsageAny:
+		return "Any"
+	case x509.ExtKeyUsageServerAuth:
+		return "ServerAuth"
+	case x509.ExtKeyUsageClientAuth:
+		return "ClientAuth"
+	case x509.ExtKeyUsageCodeSigning:
+		return "CodeSigning"
+	case x509.ExtKeyUsageEmailProtection:
+		return "EmailProtection"
+	case x509.ExtKeyUsageTimeStamping:
+		return "TimeStamping"
+	case x509.ExtKeyUsageOCSPSigning:
+		return "OCSPSigning"
+	default:
+		return fmt.Sprintf("unknown (%d)", eku)
+	}
+}
+
+// parseSCTList는 X.509v3 extension 값(extnValue)에서 TLS-encoded
+// SignedCertificateTimestampList를 파싱합니다. extnValue 자체는 그 리스트를
+// 감싼 DER OCTET STRING입니다.
+func parseSCTList(extnValue []byte) ([]SCTInfo, error) {
+	var listBytes []byte
+	if _, err := asn1.Unmarshal(extnValue, &listBytes); err != nil {
+		return nil, fmt.Errorf("failed to unwrap SCT list extension: %w", err)
+	}
+	if len(listBytes) < 2 {
+		return nil, fmt.Errorf("SCT list too short")
+	}
+
+	length := int(binary.BigEndian.Uint16(listBytes[0:2]))
+	data := listBytes[2:]
+	if len(data) < length {
+		return nil, fmt.Errorf("SCT list truncated")
+	}
+	data = data[:length]
+
+	var scts []SCTInfo
+	for len(data) >= 2 {
+		sctLen := int(binary.BigEndian.Uint16(data[0:2]))
+		data = data[2:]
+		if len(data) < sctLen {
+			break
+		}
+		sct, err := parseSingleSCT(data[:sctLen])
+		if err == nil {
+			scts = append(scts, sct)
+		}
+		data = data[sctLen:]
+	}
+	return scts, nil
+}
+
+// parseSingleSCT는 하나의 SCT 구조체(version, log_id, timestamp, extensions, signature)를
+// 파싱하여 로그 ID와 타임스탬프를 추출합니다.
+func parseSingleSCT(b []byte) (SCTInfo, error) {
+	const headerLen = 1 + 32 + 8 // version + log_id + timestamp
+	if len(b) < headerLen {
+		return SCTInfo{}, fmt.Errorf("SCT too short")
+	}
+
+	version := int(b[0])
+	logID := b[1:33]
+	timestampMillis := binary.BigEndian.Uint64(b[33:41])
+
+	return SCTInfo{
+		Version:   version,
+		LogID:     hex.EncodeToString(logID),
+		Timestamp: time.UnixMilli(int64(timestampMillis)).UTC(),
+	}, nil
+}
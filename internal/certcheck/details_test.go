@@ -0,0 +1,68 @@
+package certcheck
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestPublicKeyDetailsECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	algo, size := publicKeyDetails(&key.PublicKey)
+	if algo != "ECDSA" {
+		t.Fatalf("expected ECDSA, got %s", algo)
+	}
+	if size != "P-256 (256 bits)" {
+		t.Fatalf("unexpected size: %s", size)
+	}
+}
+
+func TestParseSCTList(t *testing.T) {
+	logID := make([]byte, 32)
+	for i := range logID {
+		logID[i] = byte(i)
+	}
+
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tsMillis := uint64(ts.UnixMilli())
+
+	sct := make([]byte, 0, 43)
+	sct = append(sct, 0) // version
+	sct = append(sct, logID...)
+	tsBuf := make([]byte, 8)
+	binary.BigEndian.PutUint64(tsBuf, tsMillis)
+	sct = append(sct, tsBuf...)
+	sct = append(sct, 0, 0) // extensions length = 0
+
+	sctEntry := make([]byte, 2)
+	binary.BigEndian.PutUint16(sctEntry, uint16(len(sct)))
+	sctEntry = append(sctEntry, sct...)
+
+	listBody := make([]byte, 2)
+	binary.BigEndian.PutUint16(listBody, uint16(len(sctEntry)))
+	listBody = append(listBody, sctEntry...)
+
+	extnValue, err := asn1.Marshal(listBody)
+	if err != nil {
+		t.Fatalf("failed to marshal extension value: %v", err)
+	}
+
+	scts, err := parseSCTList(extnValue)
+	if err != nil {
+		t.Fatalf("parseSCTList failed: %v", err)
+	}
+	if len(scts) != 1 {
+		t.Fatalf("expected 1 SCT, got %d", len(scts))
+	}
+	if !scts[0].Timestamp.Equal(ts) {
+		t.Fatalf("expected timestamp %s, got %s", ts, scts[0].Timestamp)
+	}
+}
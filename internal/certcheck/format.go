@@ -0,0 +1,57 @@
+package certcheck
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatPEM은 응답에 포함된 인증서 체인 전체를 PEM 블록들을 이어붙인 문자열로
+// 반환합니다 (format=pem 쿼리 파라미터용).
+func FormatPEM(resp *Response) string {
+	var sb strings.Builder
+	for _, cert := range resp.Certificates {
+		sb.WriteString(cert.PEM)
+	}
+	return sb.String()
+}
+
+// FormatText는 `openssl s_client -showcerts`와 비슷한 사람이 읽기 좋은 텍스트 요약을
+// 반환합니다 (format=text 쿼리 파라미터용).
+func FormatText(resp *Response) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "Target: %s\n", resp.TargetURL)
+	fmt.Fprintf(&sb, "Protocol: %s\n", resp.Protocol)
+	fmt.Fprintf(&sb, "TLS version: %s\n", resp.TLSVersion)
+	fmt.Fprintf(&sb, "Cipher suite: %s\n", resp.CipherSuite)
+	fmt.Fprintf(&sb, "Chain validation: %s\n", resp.ChainValidation)
+
+	for i, cert := range resp.Certificates {
+		fmt.Fprintf(&sb, "\n--- Certificate %d ---\n", i)
+		fmt.Fprintf(&sb, "Subject: %s\n", cert.Subject)
+		fmt.Fprintf(&sb, "Issuer: %s\n", cert.Issuer)
+		fmt.Fprintf(&sb, "Not before: %s\n", cert.NotBefore)
+		fmt.Fprintf(&sb, "Not after: %s\n", cert.NotAfter)
+		fmt.Fprintf(&sb, "Serial: %s\n", cert.SerialNumberHex)
+		fmt.Fprintf(&sb, "Public key: %s %s\n", cert.PublicKeyAlgorithm, cert.PublicKeySize)
+		fmt.Fprintf(&sb, "SHA-1: %s\n", cert.SHA1Fingerprint)
+		fmt.Fprintf(&sb, "SHA-256: %s\n", cert.SHA256Fingerprint)
+		if len(cert.DNSNames) > 0 {
+			fmt.Fprintf(&sb, "DNS names: %s\n", strings.Join(cert.DNSNames, ", "))
+		}
+		if len(cert.OCSPServer) > 0 {
+			fmt.Fprintf(&sb, "OCSP server: %s\n", strings.Join(cert.OCSPServer, ", "))
+		}
+		if len(cert.CRLDistributionPoints) > 0 {
+			fmt.Fprintf(&sb, "CRL distribution points: %s\n", strings.Join(cert.CRLDistributionPoints, ", "))
+		}
+		if cert.Revocation != nil {
+			fmt.Fprintf(&sb, "Revocation: %s (%s)\n", cert.Revocation.Status, cert.Revocation.Method)
+		}
+		for _, sct := range cert.SCTs {
+			fmt.Fprintf(&sb, "SCT: log_id=%s timestamp=%s\n", sct.LogID, sct.Timestamp)
+		}
+	}
+
+	return sb.String()
+}
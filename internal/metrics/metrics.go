@@ -0,0 +1,233 @@
+// Package metrics는 Netlify HTTP 서버의 /metrics 엔드포인트를 위한 Prometheus
+// Collector를 제공합니다. 스크레이프가 들어올 때마다 대상에 TLS 프로브를 수행하되,
+// 스크레이프 트래픽이 대상 서버를 과도하게 두드리지 않도록 TTL 캐시를 둡니다.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/zasfe/ssl-checker-go/internal/certcheck"
+	"github.com/zasfe/ssl-checker-go/internal/starttls"
+	"github.com/zasfe/ssl-checker-go/internal/trust"
+)
+
+// DefaultCacheTTL은 Cache의 ttl이 지정되지 않았을 때 사용하는 기본값입니다.
+const DefaultCacheTTL = 10 * time.Minute
+
+// probeTimeout은 각 타겟 프로브에 대한 개별 타임아웃입니다.
+const probeTimeout = 10 * time.Second
+
+type probeResult struct {
+	resp      *certcheck.Response
+	err       error
+	duration  time.Duration
+	expiresAt time.Time
+}
+
+// Cache는 타겟별 프로브 결과를 ttl 동안 재사용하는 프로세스 내 캐시입니다.
+type Cache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]probeResult
+}
+
+// NewCache는 ttl 기간만큼 프로브 결과를 캐시하는 Cache를 만듭니다. ttl이 0 이하이면
+// DefaultCacheTTL을 사용합니다.
+func NewCache(ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Cache{ttl: ttl, entries: make(map[string]probeResult)}
+}
+
+// Probe는 target에 대한 캐시된 결과를 반환하거나, 캐시가 만료되었으면 새로 프로브합니다.
+func (c *Cache) Probe(target string) probeResult {
+	c.mu.Lock()
+	if cached, ok := c.entries[target]; ok && time.Now().Before(cached.expiresAt) {
+		c.mu.Unlock()
+		return cached
+	}
+	c.mu.Unlock()
+
+	result := runProbe(target, c.ttl)
+
+	c.mu.Lock()
+	c.entries[target] = result
+	c.mu.Unlock()
+
+	return result
+}
+
+func runProbe(target string, ttl time.Duration) probeResult {
+	address, serverName := parseTarget(target)
+
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := certcheck.Check(ctx, address, serverName, starttls.ProtocolHTTPS, false, trust.StoreSystem, nil)
+	duration := time.Since(start)
+
+	return probeResult{resp: resp, err: err, duration: duration, expiresAt: time.Now().Add(ttl)}
+}
+
+// parseTarget은 "host" 또는 "host:port" 형태의 타겟 문자열을 TLS 연결용 주소와
+// SNI/검증에 사용할 서버 이름으로 분리합니다. 포트가 없으면 443을 기본값으로 씁니다.
+func parseTarget(target string) (address, serverName string) {
+	host, port, err := splitHostPort(target)
+	if err != nil {
+		return target + ":443", target
+	}
+	return host + ":" + port, host
+}
+
+func splitHostPort(target string) (host, port string, err error) {
+	if idx := strings.LastIndex(target, ":"); idx != -1 {
+		return target[:idx], target[idx+1:], nil
+	}
+	return target, "443", nil
+}
+
+// Collector는 prometheus.Collector를 구현하며, targets에 있는 각 호스트에 대해
+// 인증서 만료/체인 유효성/프로브 소요 시간 지표를 노출합니다.
+type Collector struct {
+	targets []string
+	cache   *Cache
+
+	notAfter      *prometheus.Desc
+	chainValid    *prometheus.Desc
+	probeDuration *prometheus.Desc
+	probeErrors   *prometheus.Desc
+	daysRemaining *prometheus.Desc
+}
+
+// NewCollector는 targets를 cache를 통해 프로브하는 Collector를 만듭니다.
+func NewCollector(targets []string, cache *Cache) *Collector {
+	return &Collector{
+		targets: targets,
+		cache:   cache,
+		notAfter: prometheus.NewDesc(
+			"ssl_cert_not_after_timestamp_seconds",
+			"Unix timestamp (seconds) when the leaf certificate expires.",
+			[]string{"host", "issuer", "serial"}, nil,
+		),
+		chainValid: prometheus.NewDesc(
+			"ssl_cert_chain_valid",
+			"1 if the certificate chain validated successfully, 0 otherwise.",
+			[]string{"host"}, nil,
+		),
+		probeDuration: prometheus.NewDesc(
+			"ssl_cert_probe_duration_seconds",
+			"Time taken to complete the TLS probe.",
+			[]string{"host"}, nil,
+		),
+		probeErrors: prometheus.NewDesc(
+			"ssl_cert_probe_errors_total",
+			"1 if the most recent probe of the host failed, labeled by reason. The value does not accumulate across probes.",
+			[]string{"host", "reason"}, nil,
+		),
+		daysRemaining: prometheus.NewDesc(
+			"ssl_cert_days_remaining",
+			"Days remaining until the leaf certificate expires.",
+			[]string{"host"}, nil,
+		),
+	}
+}
+
+// Describe는 prometheus.Collector를 만족시킵니다.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.notAfter
+	ch <- c.chainValid
+	ch <- c.probeDuration
+	ch <- c.probeErrors
+	ch <- c.daysRemaining
+}
+
+// Collect는 각 타겟을 (캐시를 경유해) 프로브하고 지표로 변환합니다.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, target := range c.targets {
+		c.collectOne(ch, target)
+	}
+}
+
+func (c *Collector) collectOne(ch chan<- prometheus.Metric, target string) {
+	result := c.cache.Probe(target)
+
+	ch <- prometheus.MustNewConstMetric(c.probeDuration, prometheus.GaugeValue, result.duration.Seconds(), target)
+
+	if result.err != nil {
+		ch <- prometheus.MustNewConstMetric(c.probeErrors, prometheus.CounterValue, 1, target, probeErrorReason(result.err))
+		return
+	}
+
+	resp := result.resp
+	chainValid := 0.0
+	if resp.ChainValidation == "Certificate chain is valid." {
+		chainValid = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.chainValid, prometheus.GaugeValue, chainValid, target)
+
+	if len(resp.Certificates) == 0 {
+		return
+	}
+	leaf := resp.Certificates[0]
+	ch <- prometheus.MustNewConstMetric(c.notAfter, prometheus.GaugeValue, float64(leaf.NotAfter.Unix()), target, leaf.Issuer, leaf.SerialNumberHex)
+	daysRemaining := time.Until(leaf.NotAfter).Hours() / 24
+	ch <- prometheus.MustNewConstMetric(c.daysRemaining, prometheus.GaugeValue, daysRemaining, target)
+}
+
+// probeErrorReason은 err를 고정된 소수의 원인 문자열로 매핑합니다. probeErrors는
+// host와 reason 레이블 조합마다 별도의 시계열이 되므로, 연결 주소나 OS 에러 문구처럼
+// 가변적인 원시 에러 텍스트를 레이블로 쓰면 카디널리티가 끝없이 늘어납니다.
+func probeErrorReason(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "failed to connect to"):
+		return "dial_failed"
+	case strings.Contains(msg, "TLS handshake failed"):
+		return "handshake_failed"
+	case strings.Contains(msg, "did not provide any certificates"):
+		return "no_certificates"
+	default:
+		return "other"
+	}
+}
+
+// LoadTargetsFile은 줄바꿈으로 구분된 호스트 목록 파일을 읽습니다. 빈 줄과 '#'으로
+// 시작하는 줄은 무시합니다.
+func LoadTargetsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	return targets, scanner.Err()
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseTarget(t *testing.T) {
+	cases := []struct {
+		target          string
+		wantAddress     string
+		wantServerName  string
+	}{
+		{"example.com", "example.com:443", "example.com"},
+		{"example.com:8443", "example.com:8443", "example.com"},
+	}
+
+	for _, c := range cases {
+		address, serverName := parseTarget(c.target)
+		if address != c.wantAddress || serverName != c.wantServerName {
+			t.Errorf("parseTarget(%q) = (%q, %q), want (%q, %q)", c.target, address, serverName, c.wantAddress, c.wantServerName)
+		}
+	}
+}
+
+func TestNewCacheDefaultsTTL(t *testing.T) {
+	c := NewCache(0)
+	if c.ttl != DefaultCacheTTL {
+		t.Fatalf("expected default TTL %s, got %s", DefaultCacheTTL, c.ttl)
+	}
+}
+
+func TestProbeErrorReasonIsBounded(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("failed to connect via TLS: %w", fmt.Errorf("failed to connect to 10.0.0.1:443: connection refused")), "dial_failed"},
+		{fmt.Errorf("failed to connect via TLS: %w", fmt.Errorf("TLS handshake failed: remote error: tls: bad certificate")), "handshake_failed"},
+		{fmt.Errorf("server did not provide any certificates"), "no_certificates"},
+		{errors.New("something unexpected"), "other"},
+	}
+
+	for _, c := range cases {
+		if got := probeErrorReason(c.err); got != c.want {
+			t.Errorf("probeErrorReason(%q) = %q, want %q", c.err, got, c.want)
+		}
+	}
+}
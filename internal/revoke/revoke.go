@@ -0,0 +1,236 @@
+// Package revoke는 개별 인증서의 폐기(revocation) 여부를 OCSP와 CRL을 통해 확인합니다.
+//
+// cfssl의 revoke 패키지와 동일한 정책을 따릅니다: 두 소스 중 하나라도 폐기(revoked)라고
+// 답하면 즉시 폐기로 간주하고, 둘 다 응답하지 않으면 "unknown"으로 처리할 뿐 에러로
+// 취급하지 않습니다(soft-fail). 호출자가 strict 모드를 원하면 Result.Status가
+// StatusUnknown일 때 직접 실패 처리를 결정하면 됩니다.
+package revoke
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// Status는 폐기 확인의 결과 상태입니다.
+type Status string
+
+const (
+	StatusGood    Status = "good"
+	StatusRevoked Status = "revoked"
+	StatusUnknown Status = "unknown"
+)
+
+// Method는 폐기 여부를 판단하는 데 사용된 방법입니다.
+type Method string
+
+const (
+	MethodOCSP Method = "ocsp"
+	MethodCRL  Method = "crl"
+	MethodNone Method = ""
+)
+
+// Result는 단일 인증서에 대한 폐기 확인 결과입니다.
+type Result struct {
+	Status     Status     `json:"status"`
+	Method     Method     `json:"method,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	NextUpdate *time.Time `json:"next_update,omitempty"`
+	OCSPError  string     `json:"ocsp_error,omitempty"`
+	CRLError   string     `json:"crl_error,omitempty"`
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Check는 cert의 OCSP 응답자와 CRL 배포 지점을 모두 조회하여 폐기 여부를 판단합니다.
+// issuer는 cert에 서명한 발급자 인증서여야 합니다 (OCSP 요청 생성 및 CRL 서명 검증에 사용).
+func Check(ctx context.Context, cert, issuer *x509.Certificate) Result {
+	var ocspResult, crlResult *Result
+
+	if r, err := checkOCSP(ctx, cert, issuer); err != nil {
+		ocspResult = &Result{Status: StatusUnknown, OCSPError: err.Error()}
+	} else {
+		ocspResult = r
+	}
+
+	if r, err := checkCRL(ctx, cert, issuer); err != nil {
+		crlResult = &Result{Status: StatusUnknown, CRLError: err.Error()}
+	} else {
+		crlResult = r
+	}
+
+	// 둘 중 하나라도 revoked라고 하면 revoked로 취급합니다 (hard-fail on revoked).
+	if ocspResult.Status == StatusRevoked {
+		ocspResult.CRLError = crlResult.CRLError
+		return *ocspResult
+	}
+	if crlResult.Status == StatusRevoked {
+		crlResult.OCSPError = ocspResult.OCSPError
+		return *crlResult
+	}
+
+	// 둘 중 하나가 good이면 good으로 취급합니다.
+	if ocspResult.Status == StatusGood {
+		ocspResult.CRLError = crlResult.CRLError
+		return *ocspResult
+	}
+	if crlResult.Status == StatusGood {
+		crlResult.OCSPError = ocspResult.OCSPError
+		return *crlResult
+	}
+
+	// 둘 다 연결에 실패했거나 상태를 알 수 없는 경우 unknown으로 soft-fail합니다.
+	return Result{
+		Status:    StatusUnknown,
+		OCSPError: ocspResult.OCSPError,
+		CRLError:  crlResult.CRLError,
+	}
+}
+
+func checkOCSP(ctx context.Context, cert, issuer *x509.Certificate) (*Result, error) {
+	if len(cert.OCSPServer) == 0 {
+		return nil, fmt.Errorf("certificate has no OCSP server")
+	}
+
+	reqBytes, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OCSP request: %w", err)
+	}
+
+	var lastErr error
+	for _, server := range cert.OCSPServer {
+		resp, err := postOCSP(ctx, server, reqBytes, issuer)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return ocspResultFromResponse(resp), nil
+	}
+	return nil, lastErr
+}
+
+func postOCSP(ctx context.Context, server string, reqBytes []byte, issuer *x509.Certificate) (*ocsp.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+	httpReq.Header.Set("Accept", "application/ocsp-response")
+
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder %s returned status %d", server, httpResp.StatusCode)
+	}
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ocsp.ParseResponse(body, issuer)
+}
+
+func ocspResultFromResponse(resp *ocsp.Response) *Result {
+	r := &Result{Method: MethodOCSP}
+	if !resp.NextUpdate.IsZero() {
+		nu := resp.NextUpdate
+		r.NextUpdate = &nu
+	}
+	switch resp.Status {
+	case ocsp.Revoked:
+		r.Status = StatusRevoked
+		ra := resp.RevokedAt
+		r.RevokedAt = &ra
+	case ocsp.Good:
+		r.Status = StatusGood
+	default:
+		r.Status = StatusUnknown
+	}
+	return r
+}
+
+func checkCRL(ctx context.Context, cert, issuer *x509.Certificate) (*Result, error) {
+	if len(cert.CRLDistributionPoints) == 0 {
+		return nil, fmt.Errorf("certificate has no CRL distribution points")
+	}
+
+	var lastErr error
+	for _, url := range cert.CRLDistributionPoints {
+		// LDAP을 통한 CRL 배포는 지원하지 않습니다.
+		if strings.HasPrefix(strings.ToLower(url), "ldap://") {
+			continue
+		}
+
+		list, err := fetchCRL(ctx, url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// issuer의 공개키로 서명을 검증하지 못한 CRL은 위조됐을 수 있으므로
+		// 신뢰하지 않고 다음 배포 지점으로 넘어갑니다.
+		if err := issuer.CheckCRLSignature(list); err != nil {
+			lastErr = fmt.Errorf("CRL from %s has invalid signature: %w", url, err)
+			continue
+		}
+		return crlResultFromList(cert, list), nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no usable (non-LDAP) CRL distribution points")
+	}
+	return nil, lastErr
+}
+
+func fetchCRL(ctx context.Context, url string) (*pkix.CertificateList, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CRL endpoint %s returned status %d", url, httpResp.StatusCode)
+	}
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCRL(body)
+}
+
+func crlResultFromList(cert *x509.Certificate, list *pkix.CertificateList) *Result {
+	r := &Result{Method: MethodCRL, Status: StatusGood}
+	nu := list.TBSCertList.NextUpdate
+	if !nu.IsZero() {
+		r.NextUpdate = &nu
+	}
+
+	for _, revoked := range list.TBSCertList.RevokedCertificates {
+		if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+			r.Status = StatusRevoked
+			ra := revoked.RevocationTime
+			r.RevokedAt = &ra
+			break
+		}
+	}
+	return r
+}
@@ -0,0 +1,233 @@
+package revoke
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func makeTestCert(t *testing.T, ocspServer string) (cert, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey) {
+	t.Helper()
+
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate issuer key: %v", err)
+	}
+
+	issuerTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Test CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	issuerDER, err := x509.CreateCertificate(rand.Reader, issuerTemplate, issuerTemplate, &issuerKey.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("failed to create issuer cert: %v", err)
+	}
+	issuer, err = x509.ParseCertificate(issuerDER)
+	if err != nil {
+		t.Fatalf("failed to parse issuer cert: %v", err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate leaf key: %v", err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "leaf.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		OCSPServer:   []string{ocspServer},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, issuer, &leafKey.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("failed to create leaf cert: %v", err)
+	}
+	cert, err = x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatalf("failed to parse leaf cert: %v", err)
+	}
+
+	return cert, issuer, issuerKey
+}
+
+func makeTestCertWithCRL(t *testing.T, crlURL string) (cert, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey) {
+	t.Helper()
+
+	issuerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate issuer key: %v", err)
+	}
+
+	issuerTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Test CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	issuerDER, err := x509.CreateCertificate(rand.Reader, issuerTemplate, issuerTemplate, &issuerKey.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("failed to create issuer cert: %v", err)
+	}
+	issuer, err = x509.ParseCertificate(issuerDER)
+	if err != nil {
+		t.Fatalf("failed to parse issuer cert: %v", err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate leaf key: %v", err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "leaf.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		CRLDistributionPoints: []string{crlURL},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, issuer, &leafKey.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("failed to create leaf cert: %v", err)
+	}
+	cert, err = x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatalf("failed to parse leaf cert: %v", err)
+	}
+
+	return cert, issuer, issuerKey
+}
+
+func TestCheckOCSPGood(t *testing.T) {
+	var cert, issuer *x509.Certificate
+	var issuerKey *ecdsa.PrivateKey
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		template := ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: cert.SerialNumber,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		respBytes, err := ocsp.CreateResponse(issuer, issuer, template, issuerKey)
+		if err != nil {
+			t.Fatalf("failed to create fake OCSP response: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(respBytes)
+	}))
+	defer server.Close()
+
+	cert, issuer, issuerKey = makeTestCert(t, server.URL)
+
+	result := Check(context.Background(), cert, issuer)
+	if result.Status != StatusGood {
+		t.Fatalf("expected status good, got %s (ocsp_error=%q, crl_error=%q)", result.Status, result.OCSPError, result.CRLError)
+	}
+	if result.Method != MethodOCSP {
+		t.Fatalf("expected method ocsp, got %s", result.Method)
+	}
+}
+
+func TestCheckOCSPRevoked(t *testing.T) {
+	var cert, issuer *x509.Certificate
+	var issuerKey *ecdsa.PrivateKey
+	revokedAt := time.Now().Add(-time.Minute)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		template := ocsp.Response{
+			Status:       ocsp.Revoked,
+			SerialNumber: cert.SerialNumber,
+			RevokedAt:    revokedAt,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		respBytes, err := ocsp.CreateResponse(issuer, issuer, template, issuerKey)
+		if err != nil {
+			t.Fatalf("failed to create fake OCSP response: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(respBytes)
+	}))
+	defer server.Close()
+
+	cert, issuer, issuerKey = makeTestCert(t, server.URL)
+
+	result := Check(context.Background(), cert, issuer)
+	if result.Status != StatusRevoked {
+		t.Fatalf("expected status revoked, got %s", result.Status)
+	}
+	if result.RevokedAt == nil {
+		t.Fatalf("expected RevokedAt to be set")
+	}
+}
+
+func TestCheckUnreachable(t *testing.T) {
+	cert, issuer, _ := makeTestCert(t, "http://127.0.0.1:0")
+
+	result := Check(context.Background(), cert, issuer)
+	if result.Status != StatusUnknown {
+		t.Fatalf("expected status unknown when unreachable, got %s", result.Status)
+	}
+}
+
+func TestCheckCRLForgedSignatureRejected(t *testing.T) {
+	var cert, issuer *x509.Certificate
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// forgerKey/forger is deliberately not the real issuer: the CRL is
+		// well-formed and internally consistent but signed by an attacker
+		// who controls the CRL distribution point, not by issuer.
+		forgerKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("failed to generate forger key: %v", err)
+		}
+		forgerTemplate := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      issuer.Subject,
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			IsCA:         true,
+			KeyUsage:     x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		}
+		forgerDER, err := x509.CreateCertificate(rand.Reader, forgerTemplate, forgerTemplate, &forgerKey.PublicKey, forgerKey)
+		if err != nil {
+			t.Fatalf("failed to create forger cert: %v", err)
+		}
+		forger, err := x509.ParseCertificate(forgerDER)
+		if err != nil {
+			t.Fatalf("failed to parse forger cert: %v", err)
+		}
+
+		crlBytes, err := forger.CreateCRL(rand.Reader, forgerKey, nil, time.Now(), time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("failed to create forged CRL: %v", err)
+		}
+		w.Write(crlBytes)
+	}))
+	defer server.Close()
+
+	cert, issuer, _ = makeTestCertWithCRL(t, server.URL)
+
+	result := Check(context.Background(), cert, issuer)
+	if result.Status != StatusUnknown {
+		t.Fatalf("expected status unknown for forged CRL, got %s", result.Status)
+	}
+	if result.CRLError == "" {
+		t.Fatalf("expected CRLError to explain the signature rejection")
+	}
+}
@@ -0,0 +1,310 @@
+// Package starttls는 STARTTLS 계열 프로토콜(SMTP, IMAP, POP3, FTP, LDAP)과
+// 인라인 TLS 협상 프로토콜(PostgreSQL, MySQL)의 평문 프리앰블을 처리한 뒤,
+// 같은 TCP 연결 위에서 TLS 핸드셰이크를 수행합니다. "https"(또는 빈 문자열)는
+// 프리앰블 없이 즉시 TLS를 시작하는 기존 동작과 동일합니다.
+package starttls
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// Protocol은 핸드셰이크 전에 수행할 평문 프리앰블의 종류를 나타냅니다.
+type Protocol string
+
+const (
+	ProtocolHTTPS    Protocol = "https"
+	ProtocolSMTP     Protocol = "smtp"
+	ProtocolIMAP     Protocol = "imap"
+	ProtocolPOP3     Protocol = "pop3"
+	ProtocolFTP      Protocol = "ftp"
+	ProtocolLDAP     Protocol = "ldap"
+	ProtocolPostgres Protocol = "postgres"
+	ProtocolMySQL    Protocol = "mysql"
+)
+
+// defaultPorts는 protocol에 포트가 명시되지 않았을 때 사용할 기본 포트입니다.
+var defaultPorts = map[Protocol]string{
+	ProtocolHTTPS:    "443",
+	ProtocolSMTP:     "587",
+	ProtocolIMAP:     "143",
+	ProtocolPOP3:     "110",
+	ProtocolFTP:      "21",
+	ProtocolLDAP:     "389",
+	ProtocolPostgres: "5432",
+	ProtocolMySQL:    "3306",
+}
+
+// DefaultPort는 protocol에 해당하는 기본 포트를 반환합니다. 알 수 없는 프로토콜이면
+// ok는 false입니다.
+func DefaultPort(protocol Protocol) (port string, ok bool) {
+	port, ok = defaultPorts[protocol]
+	return port, ok
+}
+
+// Dial은 address로 평문 TCP 연결을 맺고, protocol에 맞는 프리앰블을 주고받은 뒤
+// 같은 연결 위에서 TLS 클라이언트 핸드셰이크를 수행합니다.
+func Dial(ctx context.Context, address string, protocol Protocol, tlsConfig *tls.Config) (*tls.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	if err := negotiate(conn, protocol); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// negotiate는 TLS 핸드셰이크 전에 필요한 평문 프리앰블을 수행합니다.
+func negotiate(conn net.Conn, protocol Protocol) error {
+	switch protocol {
+	case "", ProtocolHTTPS:
+		return nil
+	case ProtocolSMTP:
+		return negotiateSMTP(conn)
+	case ProtocolIMAP:
+		return negotiateIMAP(conn)
+	case ProtocolPOP3:
+		return negotiatePOP3(conn)
+	case ProtocolFTP:
+		return negotiateFTP(conn)
+	case ProtocolLDAP:
+		return negotiateLDAP(conn)
+	case ProtocolPostgres:
+		return negotiatePostgres(conn)
+	case ProtocolMySQL:
+		return negotiateMySQL(conn)
+	default:
+		return fmt.Errorf("unsupported protocol %q", protocol)
+	}
+}
+
+func negotiateSMTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+
+	if _, err := readSMTPReply(r); err != nil { // 220 banner
+		return fmt.Errorf("smtp: failed to read banner: %w", err)
+	}
+	if _, err := fmt.Fprintf(conn, "EHLO ssl-checker\r\n"); err != nil {
+		return err
+	}
+	if _, err := readSMTPReply(r); err != nil { // 250 EHLO response
+		return fmt.Errorf("smtp: EHLO failed: %w", err)
+	}
+	if _, err := fmt.Fprintf(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	line, err := readSMTPReply(r)
+	if err != nil {
+		return fmt.Errorf("smtp: STARTTLS failed: %w", err)
+	}
+	if !strings.HasPrefix(line, "220") {
+		return fmt.Errorf("smtp: unexpected STARTTLS response: %s", line)
+	}
+	return nil
+}
+
+// readSMTPReply는 "250-..." 형태의 멀티라인 응답을 마지막 줄까지 읽고 마지막 줄을 반환합니다.
+func readSMTPReply(r *bufio.Reader) (string, error) {
+	var last string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		last = line
+		if len(line) < 4 || line[3] == ' ' {
+			break
+		}
+	}
+	if len(last) == 0 || last[0] < '2' || last[0] > '3' {
+		return last, fmt.Errorf("unexpected reply: %s", last)
+	}
+	return last, nil
+}
+
+func negotiateIMAP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+
+	if _, err := r.ReadString('\n'); err != nil { // "* OK ..." greeting
+		return fmt.Errorf("imap: failed to read greeting: %w", err)
+	}
+	if _, err := fmt.Fprintf(conn, "a1 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("imap: failed to read STARTTLS response: %w", err)
+	}
+	if !strings.Contains(line, "a1 OK") {
+		return fmt.Errorf("imap: unexpected STARTTLS response: %s", strings.TrimSpace(line))
+	}
+	return nil
+}
+
+func negotiatePOP3(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+
+	if line, err := r.ReadString('\n'); err != nil || !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("pop3: unexpected greeting: %w", err)
+	}
+	if _, err := fmt.Fprintf(conn, "STLS\r\n"); err != nil {
+		return err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("pop3: failed to read STLS response: %w", err)
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("pop3: unexpected STLS response: %s", strings.TrimSpace(line))
+	}
+	return nil
+}
+
+func negotiateFTP(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+
+	if line, err := r.ReadString('\n'); err != nil || !strings.HasPrefix(line, "220") {
+		return fmt.Errorf("ftp: unexpected banner: %w", err)
+	}
+	if _, err := fmt.Fprintf(conn, "AUTH TLS\r\n"); err != nil {
+		return err
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("ftp: failed to read AUTH TLS response: %w", err)
+	}
+	if !strings.HasPrefix(line, "234") {
+		return fmt.Errorf("ftp: unexpected AUTH TLS response: %s", strings.TrimSpace(line))
+	}
+	return nil
+}
+
+// negotiateLDAP은 RFC 4511의 StartTLS extended operation을 수행합니다. messageID 1,
+// requestName 1.3.6.1.4.1.1466.20037로 고정된 최소한의 BER 인코딩을 사용합니다.
+func negotiateLDAP(conn net.Conn) error {
+	const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+	oidTag := append([]byte{0x80, byte(len(startTLSOID))}, []byte(startTLSOID)...)
+	extendedReq := append([]byte{0x77, byte(len(oidTag))}, oidTag...)
+	messageID := []byte{0x02, 0x01, 0x01} // INTEGER 1
+	envelope := append(messageID, extendedReq...)
+	ldapMessage := append([]byte{0x30, byte(len(envelope))}, envelope...)
+
+	if _, err := conn.Write(ldapMessage); err != nil {
+		return fmt.Errorf("ldap: failed to send StartTLS request: %w", err)
+	}
+
+	msg, err := readLDAPMessage(conn)
+	if err != nil {
+		return fmt.Errorf("ldap: failed to read StartTLS response: %w", err)
+	}
+	// extendedResponse의 resultCode(0)가 success임을 간단히 확인합니다: 태그 0x0A 0x01 0x00.
+	if !containsSuccessCode(msg) {
+		return fmt.Errorf("ldap: StartTLS was not accepted")
+	}
+	return nil
+}
+
+// readLDAPMessage는 짧은 형식(short-form, 길이 <128바이트) BER 길이만 지원한다고
+// 가정하고 LDAPMessage 하나를 온전히 읽습니다. net.Conn.Read는 메시지 전체가 한 번의
+// 호출로 도착한다고 보장하지 않으므로, 선언된 길이만큼 io.ReadFull로 읽어들입니다.
+func readLDAPMessage(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	if header[1]&0x80 != 0 {
+		return nil, fmt.Errorf("long-form BER length not supported")
+	}
+
+	body := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+	return append(header, body...), nil
+}
+
+func containsSuccessCode(b []byte) bool {
+	for i := 0; i+2 < len(b); i++ {
+		if b[i] == 0x0A && b[i+1] == 0x01 && b[i+2] == 0x00 {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiatePostgres는 PostgreSQL의 SSLRequest 메시지를 보내고 서버가 'S'로 응답하는지
+// 확인합니다 (반대인 'N'은 서버가 TLS를 지원하지 않는다는 뜻입니다).
+func negotiatePostgres(conn net.Conn) error {
+	const sslRequestCode = 80877103
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint32(msg[0:4], 8)
+	binary.BigEndian.PutUint32(msg[4:8], sslRequestCode)
+
+	if _, err := conn.Write(msg); err != nil {
+		return fmt.Errorf("postgres: failed to send SSLRequest: %w", err)
+	}
+
+	reply := make([]byte, 1)
+	if _, err := conn.Read(reply); err != nil {
+		return fmt.Errorf("postgres: failed to read SSLRequest reply: %w", err)
+	}
+	if reply[0] != 'S' {
+		return fmt.Errorf("postgres: server declined TLS (replied %q)", reply[0])
+	}
+	return nil
+}
+
+// negotiateMySQL은 서버의 초기 핸드셰이크 패킷을 읽고, CLIENT_SSL과 CLIENT_PROTOCOL_41
+// capability 플래그만 설정한 SSLRequest 패킷을 보내 TLS로 전환합니다.
+func negotiateMySQL(conn net.Conn) error {
+	const clientSSL = 0x00000800
+	const clientProtocol41 = 0x00000200
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return fmt.Errorf("mysql: failed to read handshake header: %w", err)
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	seq := header[3]
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return fmt.Errorf("mysql: failed to read handshake payload: %w", err)
+	}
+
+	sslRequest := make([]byte, 32)
+	binary.LittleEndian.PutUint32(sslRequest[0:4], uint32(clientSSL|clientProtocol41))
+	binary.LittleEndian.PutUint32(sslRequest[4:8], 1<<24-1) // max packet size
+	sslRequest[8] = 45                                      // utf8mb4_general_ci
+
+	out := make([]byte, 4+len(sslRequest))
+	out[0] = byte(len(sslRequest))
+	out[1] = byte(len(sslRequest) >> 8)
+	out[2] = byte(len(sslRequest) >> 16)
+	out[3] = seq + 1
+	copy(out[4:], sslRequest)
+
+	if _, err := conn.Write(out); err != nil {
+		return fmt.Errorf("mysql: failed to send SSLRequest: %w", err)
+	}
+	return nil
+}
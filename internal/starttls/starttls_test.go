@@ -0,0 +1,198 @@
+package starttls
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+)
+
+// fakeListener starts a plain TCP listener that runs handler against each
+// accepted connection, and returns its address for use in tests.
+func fakeListener(t *testing.T, handler func(net.Conn)) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start fake listener: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		handler(conn)
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestNegotiateSMTP(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("220 fake.example.com ESMTP\r\n"))
+		r.ReadString('\n') // EHLO
+		conn.Write([]byte("250 fake.example.com\r\n"))
+		r.ReadString('\n') // STARTTLS
+		conn.Write([]byte("220 Go ahead\r\n"))
+	})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := negotiateSMTP(conn); err != nil {
+		t.Fatalf("negotiateSMTP failed: %v", err)
+	}
+}
+
+func TestNegotiatePOP3(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("+OK POP3 ready\r\n"))
+		r.ReadString('\n') // STLS
+		conn.Write([]byte("+OK Begin TLS\r\n"))
+	})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := negotiatePOP3(conn); err != nil {
+		t.Fatalf("negotiatePOP3 failed: %v", err)
+	}
+}
+
+func TestNegotiatePostgres(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		buf := make([]byte, 8)
+		conn.Read(buf)
+		conn.Write([]byte("S"))
+	})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := negotiatePostgres(conn); err != nil {
+		t.Fatalf("negotiatePostgres failed: %v", err)
+	}
+}
+
+func TestNegotiateIMAP(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("* OK IMAP4rev1 fake.example.com ready\r\n"))
+		r.ReadString('\n') // a1 STARTTLS
+		conn.Write([]byte("a1 OK Begin TLS negotiation now\r\n"))
+	})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := negotiateIMAP(conn); err != nil {
+		t.Fatalf("negotiateIMAP failed: %v", err)
+	}
+}
+
+func TestNegotiateFTP(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("220 fake FTP ready\r\n"))
+		r.ReadString('\n') // AUTH TLS
+		conn.Write([]byte("234 AUTH TLS successful\r\n"))
+	})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := negotiateFTP(conn); err != nil {
+		t.Fatalf("negotiateFTP failed: %v", err)
+	}
+}
+
+func TestNegotiateLDAP(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) {
+		defer conn.Close()
+
+		// client의 StartTLS extended request를 소비합니다: 헤더의 길이 바이트가 알려주는
+		// 만큼만 io.ReadFull로 읽습니다.
+		header := make([]byte, 2)
+		io.ReadFull(conn, header)
+		body := make([]byte, header[1])
+		io.ReadFull(conn, body)
+
+		// extendedResponse(tag 0x78), messageID 1, resultCode(0x0A 0x01 0x00) success.
+		// 일부러 두 번에 나눠 써서 TCP 세그먼트 경계를 흉내 냅니다.
+		conn.Write([]byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x78})
+		conn.Write([]byte{0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00})
+	})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := negotiateLDAP(conn); err != nil {
+		t.Fatalf("negotiateLDAP failed: %v", err)
+	}
+}
+
+func TestNegotiateMySQL(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) {
+		defer conn.Close()
+
+		// 최소한의 초기 핸드셰이크 패킷(길이 1, 시퀀스 0, 페이로드 1바이트)을 일부러
+		// 헤더와 페이로드로 나눠 써서 io.ReadFull이 필요함을 검증합니다.
+		conn.Write([]byte{0x01, 0x00, 0x00, 0x00})
+		conn.Write([]byte{0x0a})
+
+		// 클라이언트의 SSLRequest 패킷을 읽어들입니다.
+		header := make([]byte, 4)
+		io.ReadFull(conn, header)
+		length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+		payload := make([]byte, length)
+		io.ReadFull(conn, payload)
+	})
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("failed to dial fake server: %v", err)
+	}
+	defer conn.Close()
+
+	if err := negotiateMySQL(conn); err != nil {
+		t.Fatalf("negotiateMySQL failed: %v", err)
+	}
+}
+
+func TestDialUnsupportedProtocol(t *testing.T) {
+	addr := fakeListener(t, func(conn net.Conn) { conn.Close() })
+
+	_, err := Dial(context.Background(), addr, Protocol("gopher"), &tls.Config{})
+	if err == nil {
+		t.Fatalf("expected error for unsupported protocol")
+	}
+}
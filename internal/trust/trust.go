@@ -0,0 +1,56 @@
+// Package trust는 인증서 체인 검증에 사용할 신뢰 저장소(trust store)를 선택합니다.
+// 브라우저처럼 Mozilla의 루트 목록으로 검증하거나, 호출자가 제공한 커스텀 CA 번들로
+// 검증할 수 있어, Lambda 기본 이미지의 ca-certificates 패키지에만 의존하지 않습니다.
+package trust
+
+import (
+	"crypto/x509"
+	_ "embed"
+	"fmt"
+)
+
+//go:embed mozilla_roots.pem
+var mozillaRootsPEM []byte
+
+// Store는 체인 검증에 사용할 루트 인증서 출처입니다.
+type Store string
+
+const (
+	StoreSystem  Store = "system"
+	StoreMozilla Store = "mozilla"
+	StoreCustom  Store = "custom"
+)
+
+// Pool은 store에 해당하는 x509.CertPool과, 응답에 그대로 노출할 수 있는 사람이
+// 읽기 좋은 이름을 반환합니다. store가 custom이면 customPEM(요청 바디의 PEM 번들)이
+// 반드시 있어야 합니다.
+func Pool(store Store, customPEM []byte) (*x509.CertPool, string, error) {
+	switch store {
+	case "", StoreSystem:
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		return pool, "system", nil
+
+	case StoreMozilla:
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(mozillaRootsPEM) {
+			return nil, "", fmt.Errorf("failed to parse embedded Mozilla root bundle")
+		}
+		return pool, "mozilla", nil
+
+	case StoreCustom:
+		if len(customPEM) == 0 {
+			return nil, "", fmt.Errorf("trust=custom requires a PEM-encoded CA bundle in the request body")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(customPEM) {
+			return nil, "", fmt.Errorf("failed to parse custom CA bundle")
+		}
+		return pool, "custom", nil
+
+	default:
+		return nil, "", fmt.Errorf("unknown trust store %q (want system, mozilla, or custom)", store)
+	}
+}
@@ -0,0 +1,104 @@
+package trust
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func makeTestCAPEM(t *testing.T) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Test Custom CA"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create CA cert: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestPoolSystem(t *testing.T) {
+	pool, name, err := Pool(StoreSystem, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "system" {
+		t.Fatalf("expected name %q, got %q", "system", name)
+	}
+	if pool == nil {
+		t.Fatalf("expected a non-nil pool")
+	}
+}
+
+func TestPoolDefaultsToSystem(t *testing.T) {
+	_, name, err := Pool("", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "system" {
+		t.Fatalf("expected empty store to default to system, got %q", name)
+	}
+}
+
+func TestPoolMozilla(t *testing.T) {
+	pool, name, err := Pool(StoreMozilla, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "mozilla" {
+		t.Fatalf("expected name %q, got %q", "mozilla", name)
+	}
+	if len(pool.Subjects()) == 0 {
+		t.Fatalf("expected the embedded Mozilla bundle to contain at least one root")
+	}
+}
+
+func TestPoolCustom(t *testing.T) {
+	caPEM := makeTestCAPEM(t)
+
+	pool, name, err := Pool(StoreCustom, caPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if name != "custom" {
+		t.Fatalf("expected name %q, got %q", "custom", name)
+	}
+	if len(pool.Subjects()) != 1 {
+		t.Fatalf("expected exactly one root in the custom pool, got %d", len(pool.Subjects()))
+	}
+}
+
+func TestPoolCustomRequiresPEM(t *testing.T) {
+	if _, _, err := Pool(StoreCustom, nil); err == nil {
+		t.Fatalf("expected an error when trust=custom is used without a CA bundle")
+	}
+}
+
+func TestPoolCustomRejectsInvalidPEM(t *testing.T) {
+	if _, _, err := Pool(StoreCustom, []byte("not a valid PEM bundle")); err == nil {
+		t.Fatalf("expected an error for an unparsable custom CA bundle")
+	}
+}
+
+func TestPoolUnknownStore(t *testing.T) {
+	if _, _, err := Pool(Store("bogus"), nil); err == nil {
+		t.Fatalf("expected an error for an unknown trust store")
+	}
+}
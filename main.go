@@ -3,18 +3,65 @@
 package main
 
 import (
-	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/zasfe/ssl-checker-go/internal/batch"
+	"github.com/zasfe/ssl-checker-go/internal/certcheck"
+	"github.com/zasfe/ssl-checker-go/internal/metrics"
+	"github.com/zasfe/ssl-checker-go/internal/starttls"
+	"github.com/zasfe/ssl-checker-go/internal/trust"
 )
 
+// metricsCache는 /metrics 스크레이프 사이에 프로브 결과를 재사용하기 위한
+// 프로세스 전역 캐시입니다. main에서 SSL_CHECKER_METRICS_CACHE_TTL로 TTL을 설정합니다.
+var metricsCache *metrics.Cache
+
+// defaultMetricsTargets는 스크레이프 요청에 target 쿼리 파라미터가 없을 때 사용할
+// 기본 타겟 목록입니다 (SSL_CHECKER_METRICS_CONFIG 파일에서 읽습니다).
+var defaultMetricsTargets []string
+
 // main 함수는 Netlify의 웹 서버로서 애플리케이션을 실행합니다.
 func main() {
+	ttl := metrics.DefaultCacheTTL
+	if raw := os.Getenv("SSL_CHECKER_METRICS_CACHE_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil {
+			ttl = parsed
+		}
+	}
+	metricsCache = metrics.NewCache(ttl)
+
+	if configPath := os.Getenv("SSL_CHECKER_METRICS_CONFIG"); configPath != "" {
+		targets, err := metrics.LoadTargetsFile(configPath)
+		if err != nil {
+			fmt.Printf("Failed to load metrics config %s: %v\n", configPath, err)
+		} else {
+			defaultMetricsTargets = targets
+		}
+	}
+
 	// HTTP 핸들러 함수를 설정합니다.
 	http.HandleFunc("/", handleRequest)
+	http.HandleFunc("/batch", handleBatch)
+	http.HandleFunc("/metrics", handleMetrics)
+
+	// SSL_CHECKER_DOMAINS가 설정되어 있으면 autocert로 직접 TLS를 종료합니다.
+	// 그렇지 않으면 Netlify/로컬 배포에서 쓰던 기존 평문 HTTP 동작을 그대로 유지합니다.
+	if domains := os.Getenv("SSL_CHECKER_DOMAINS"); domains != "" {
+		serveAutocert(domains)
+		return
+	}
 
 	// 포트를 지정하여 서버를 실행합니다.
 	port := os.Getenv("PORT")
@@ -30,12 +77,84 @@ func main() {
 	}
 }
 
+// serveAutocert는 Let's Encrypt의 http-01 challenge와 자동 갱신을 사용해 :443에서
+// TLS를 직접 종료합니다. :80은 challenge 응답과 HTTPS로의 리다이렉트에 사용됩니다.
+func serveAutocert(domains string) {
+	hosts := strings.Split(domains, ",")
+	for i := range hosts {
+		hosts[i] = strings.TrimSpace(hosts[i])
+	}
+
+	cacheDir := os.Getenv("SSL_CHECKER_CACHE_DIR")
+	if cacheDir == "" {
+		cacheDir = "autocert-cache"
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(hosts...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      os.Getenv("SSL_CHECKER_EMAIL"),
+	}
+
+	go func() {
+		fmt.Println("Serving http-01 challenges and HTTPS redirect on :80")
+		if err := http.ListenAndServe(":80", manager.HTTPHandler(nil)); err != nil {
+			fmt.Println("Failed to start :80 challenge/redirect server:", err)
+		}
+	}()
+
+	server := &http.Server{
+		Addr:      ":443",
+		TLSConfig: manager.TLSConfig(),
+	}
+
+	fmt.Printf("Serving HTTPS on :443 for domains: %s\n", strings.Join(hosts, ", "))
+	if err := server.ListenAndServeTLS("", ""); err != nil {
+		fmt.Println("Failed to start HTTPS server:", err)
+	}
+}
+
+// handleMetrics 함수는 target 쿼리 파라미터(복수 지정 가능)로 받은 호스트들, 또는
+// SSL_CHECKER_METRICS_CONFIG로 설정된 기본 목록을 프로브하여 Prometheus 형식으로 노출합니다.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	targets := r.URL.Query()["target"]
+	if len(targets) == 0 {
+		targets = defaultMetricsTargets
+	}
+	if len(targets) == 0 {
+		http.Error(w, "At least one 'target' query parameter (or SSL_CHECKER_METRICS_CONFIG entry) is required", http.StatusBadRequest)
+		return
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics.NewCollector(targets, metricsCache))
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
 // handleRequest 함수는 HTTP 요청을 처리하고 해당 웹사이트의 SSL 인증서 만료일을 반환합니다.
 func handleRequest(w http.ResponseWriter, r *http.Request) {
 	// 쿼리 파라미터에서 URL과 debug 값을 가져옵니다.
 	query := r.URL.Query()
 	rawURL := query.Get("url")
 	debug := query.Get("debug")
+	strict, _ := strconv.ParseBool(query.Get("strict"))
+
+	protocol := starttls.Protocol(query.Get("protocol"))
+	if protocol == "" {
+		protocol = starttls.ProtocolHTTPS
+	}
+
+	trustStore := trust.Store(query.Get("trust"))
+	var customCA []byte
+	if trustStore == trust.StoreCustom {
+		var err error
+		customCA, err = io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Failed to read 'ca' PEM body", http.StatusBadRequest)
+			return
+		}
+	}
 
 	// URL이 제공되지 않으면 오류 메시지를 반환합니다.
 	if rawURL == "" {
@@ -46,9 +165,13 @@ func handleRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// URL에서 호스트와 포트를 추출합니다.
+	// URL에서 호스트와 포트를 추출합니다. scheme이 없는 bare host[:port]도 허용합니다
+	// (예: protocol=smtp&url=mail.example.com).
 	parsedURL, err := url.Parse(rawURL)
-	if err != nil || parsedURL.Scheme != "https" || parsedURL.Host == "" {
+	if err != nil || parsedURL.Host == "" {
+		parsedURL, err = url.Parse("//" + rawURL)
+	}
+	if err != nil || parsedURL.Host == "" {
 		http.Error(w, "Invalid 'url' format. Example: https://example.com:8443", http.StatusBadRequest)
 		if debug == "true" {
 			fmt.Printf("Debug: 잘못된 URL 형식 - %v\n", err)
@@ -56,14 +179,18 @@ func handleRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 기본 포트가 없는 경우 443을 추가합니다.
+	// 기본 포트가 없는 경우 protocol에 맞는 기본 포트를 추가합니다.
 	host := parsedURL.Host
 	if parsedURL.Port() == "" {
-		host = parsedURL.Hostname() + ":443"
+		port, ok := starttls.DefaultPort(protocol)
+		if !ok {
+			port = "443"
+		}
+		host = parsedURL.Hostname() + ":" + port
 	}
 
-	// 호스트로 TLS 연결을 시도합니다.
-	conn, err := tls.Dial("tcp", host, nil)
+	// 호스트로 연결을 시도하고(필요하면 STARTTLS 협상 후) 인증서 체인과 폐기 상태를 조회합니다.
+	result, err := certcheck.Check(r.Context(), host, parsedURL.Hostname(), protocol, strict, trustStore, customCA)
 	if err != nil {
 		http.Error(w, "Failed to connect to the server", http.StatusInternalServerError)
 		if debug == "true" {
@@ -71,22 +198,56 @@ func handleRequest(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	defer conn.Close()
 
-	// 인증서를 가져옵니다.
-	certs := conn.ConnectionState().PeerCertificates
-	if len(certs) == 0 {
-		http.Error(w, "No certificates found", http.StatusInternalServerError)
-		if debug == "true" {
-			fmt.Println("Debug: 인증서를 찾을 수 없습니다.")
+	switch query.Get("format") {
+	case "pem":
+		w.Header().Set("Content-Type", "application/x-pem-file")
+		w.Write([]byte(certcheck.FormatPEM(result)))
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(certcheck.FormatText(result)))
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			http.Error(w, "Failed to serialize response", http.StatusInternalServerError)
+			if debug == "true" {
+				fmt.Printf("Debug: 응답 직렬화 오류 - %v\n", err)
+			}
 		}
+	}
+}
+
+// batchRequest는 POST /batch의 요청 바디입니다.
+type batchRequest struct {
+	Targets     []batch.Target `json:"targets"`
+	Concurrency int            `json:"concurrency,omitempty"`
+}
+
+// handleBatch 함수는 여러 타겟을 한 번에 스캔하는 POST /batch 요청을 처리합니다.
+func handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed, use POST", http.StatusMethodNotAllowed)
 		return
 	}
 
-	// 첫 번째 인증서의 만료일을 가져옵니다.
-	expiry := certs[0].NotAfter
-	response := fmt.Sprintf("SSL certificate for %s expires on %s\n", rawURL, expiry.Format(time.RFC3339))
+	var req batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Targets) == 0 {
+		http.Error(w, "'targets' must contain at least one entry", http.StatusBadRequest)
+		return
+	}
+
+	if concurrencyParam := r.URL.Query().Get("concurrency"); concurrencyParam != "" {
+		if n, err := strconv.Atoi(concurrencyParam); err == nil {
+			req.Concurrency = n
+		}
+	}
+
+	results := batch.Run(r.Context(), req.Targets, req.Concurrency)
 
-	// 만료일을 응답으로 반환합니다.
-	w.Write([]byte(response))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
 }
@@ -2,35 +2,24 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/url"
-	"time"
+	"strconv"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-)
 
-// CertInfo는 개별 인증서의 상세 정보를 담는 구조체입니다.
-type CertInfo struct {
-	Subject      string    `json:"subject"`
-	Issuer       string    `json:"issuer"`
-	NotBefore    time.Time `json:"not_before"`
-	NotAfter     time.Time `json:"not_after"`
-	DNSNames     []string  `json:"dns_names,omitempty"` // 사이트 인증서에만 포함
-	IsCA         bool      `json:"is_ca"`
-	SignatureAlgo string    `json:"signature_algorithm"`
-}
+	"github.com/zasfe/ssl-checker-go/internal/batch"
+	"github.com/zasfe/ssl-checker-go/internal/certcheck"
+	"github.com/zasfe/ssl-checker-go/internal/starttls"
+	"github.com/zasfe/ssl-checker-go/internal/trust"
+)
 
-// Response는 API 응답의 전체 구조입니다.
-type Response struct {
-	TargetURL        string     `json:"target_url"`
-	Certificates     []CertInfo `json:"certificates"`
-	ChainValidation  string     `json:"chain_validation_message"`
-}
+// maxResponseBytes는 API Gateway의 6MB 페이로드 한도에 여유를 두기 위한 상한입니다.
+const maxResponseBytes = 5 * 1024 * 1024
 
 // 에러 응답을 생성하는 헬퍼 함수
 func createErrorResponse(statusCode int, message string) (events.APIGatewayProxyResponse, error) {
@@ -42,8 +31,32 @@ func createErrorResponse(statusCode int, message string) (events.APIGatewayProxy
 }
 
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.Path == "/batch" {
+		return handleBatch(ctx, request)
+	}
+
 	ip := request.QueryStringParameters["ip"]
 	hostname := request.QueryStringParameters["url"]
+	strict, _ := strconv.ParseBool(request.QueryStringParameters["strict"])
+
+	protocol := starttls.Protocol(request.QueryStringParameters["protocol"])
+	if protocol == "" {
+		protocol = starttls.ProtocolHTTPS
+	}
+
+	trustStore := trust.Store(request.QueryStringParameters["trust"])
+	var customCA []byte
+	if trustStore == trust.StoreCustom {
+		if request.IsBase64Encoded {
+			decoded, err := base64.StdEncoding.DecodeString(request.Body)
+			if err != nil {
+				return createErrorResponse(400, "Failed to decode base64 'ca' body.")
+			}
+			customCA = decoded
+		} else {
+			customCA = []byte(request.Body)
+		}
+	}
 
 	if ip == "" || hostname == "" {
 		return createErrorResponse(400, "Query parameters 'ip' and 'url' are required.")
@@ -55,73 +68,85 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		hostname = parsedURL.Host
 	}
 
+	// TCP 연결 주소 설정. protocol에 맞는 기본 포트를 사용합니다.
+	port, ok := starttls.DefaultPort(protocol)
+	if !ok {
+		port = "443"
+	}
+	address := net.JoinHostPort(ip, port)
 
-	// TCP 연결 주소 설정
-	address := net.JoinHostPort(ip, "443")
-
-	// TLS 다이얼러 설정. `InsecureSkipVerify: true`로 설정하여 직접 체인을 검증합니다.
-	dialer := &net.Dialer{Timeout: 5 * time.Second}
-	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
-		ServerName:         hostname,
-		InsecureSkipVerify: true,
-	})
+	responsePayload, err := certcheck.Check(ctx, address, hostname, protocol, strict, trustStore, customCA)
 	if err != nil {
-		return createErrorResponse(500, fmt.Sprintf("Failed to connect via TLS: %s", err.Error()))
+		return createErrorResponse(500, err.Error())
 	}
-	defer conn.Close()
 
-	// 연결 상태에서 인증서 체인 가져오기
-	certs := conn.ConnectionState().PeerCertificates
-	if len(certs) == 0 {
-		return createErrorResponse(500, "Server did not provide any certificates.")
+	switch request.QueryStringParameters["format"] {
+	case "pem":
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Body:       certcheck.FormatPEM(responsePayload),
+			Headers:    map[string]string{"Content-Type": "application/x-pem-file"},
+		}, nil
+	case "text":
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Body:       certcheck.FormatText(responsePayload),
+			Headers:    map[string]string{"Content-Type": "text/plain; charset=utf-8"},
+		}, nil
 	}
 
-	// 각 인증서 정보 파싱
-	var certInfos []CertInfo
-	for _, cert := range certs {
-		info := CertInfo{
-			Subject:      cert.Subject.String(),
-			Issuer:       cert.Issuer.String(),
-			NotBefore:    cert.NotBefore.UTC(),
-			NotAfter:     cert.NotAfter.UTC(),
-			IsCA:         cert.IsCA,
-			SignatureAlgo: cert.SignatureAlgorithm.String(),
-		}
-		// 사이트 인증서(첫 번째)에만 SANs 정보 추가
-		if len(certInfos) == 0 {
-			info.DNSNames = cert.DNSNames
-		}
-		certInfos = append(certInfos, info)
+	jsonBody, err := json.MarshalIndent(responsePayload, "", "  ")
+	if err != nil {
+		return createErrorResponse(500, "Failed to serialize response.")
 	}
 
-	// 인증서 체인 검증
-	intermediates := x509.NewCertPool()
-	for i, cert := range certs {
-		if i > 0 { // 0번째는 리프(사이트) 인증서이므로 제외
-			intermediates.AddCert(cert)
-		}
-	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Body:       string(jsonBody),
+		Headers:    map[string]string{"Content-Type": "application/json"},
+	}, nil
+}
+
+// batchRequest는 POST /batch의 요청 바디입니다.
+type batchRequest struct {
+	Targets     []batch.Target `json:"targets"`
+	Concurrency int            `json:"concurrency,omitempty"`
+}
 
-	validationOpts := x509.VerifyOptions{
-		DNSName:       hostname,
-		Intermediates: intermediates,
-		// 시스템의 루트 CA 풀을 사용
-		// Netlify(AWS Lambda) 환경에 내장된 루트 CA 목록을 사용하게 됩니다.
+// handleBatch는 여러 타겟을 한 번에 스캔합니다. API Gateway의 응답 크기 한도에 걸리지
+// 않도록, 결과를 순서대로 이어붙이면서 maxResponseBytes를 넘는 시점부터는 나머지를
+// 잘라내고 전체 호출은 실패시키지 않은 채 partial 결과를 돌려줍니다.
+func handleBatch(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	var req batchRequest
+	if err := json.Unmarshal([]byte(request.Body), &req); err != nil {
+		return createErrorResponse(400, "Invalid JSON body.")
 	}
-	
-	validationMessage := "Certificate chain is valid."
-	if _, err := certs.Verify(validationOpts); err != nil {
-		validationMessage = fmt.Sprintf("Certificate chain verification failed: %s", err.Error())
+	if len(req.Targets) == 0 {
+		return createErrorResponse(400, "'targets' must contain at least one entry.")
 	}
 
-	// 최종 응답 데이터 구성
-	responsePayload := Response{
-		TargetURL:       fmt.Sprintf("https://%s", hostname),
-		Certificates:    certInfos,
-		ChainValidation: validationMessage,
+	results := batch.Run(ctx, req.Targets, req.Concurrency)
+
+	truncated := false
+	kept := make([]batch.Result, 0, len(results))
+	size := 2 // "[]"
+	for _, result := range results {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			continue
+		}
+		size += len(encoded) + 1 // +1 for the separating comma
+		if size > maxResponseBytes {
+			truncated = true
+			break
+		}
+		kept = append(kept, result)
 	}
 
-	jsonBody, err := json.MarshalIndent(responsePayload, "", "  ")
+	jsonBody, err := json.Marshal(struct {
+		Results   []batch.Result `json:"results"`
+		Truncated bool           `json:"truncated,omitempty"`
+	}{Results: kept, Truncated: truncated})
 	if err != nil {
 		return createErrorResponse(500, "Failed to serialize response.")
 	}